@@ -0,0 +1,37 @@
+package main
+
+import "fmt"
+
+// Display abstracts an e-ink panel so the polling loop and image pipeline
+// (processNextImage, displayImage) don't depend on SPI/GPIO hardware. The
+// Waveshare 7.5" V2 panel is the default implementation (EPD); a second
+// implementation lets the program run on a dev machine. Future panel
+// variants (2.13", 4.2", 7-color ACeP) can be added as new implementations
+// without touching the main loop.
+type Display interface {
+	Init() error
+	Clear() error
+	Draw(buffer []byte) error
+	Sleep()
+	Width() int
+	Height() int
+}
+
+// PartialDisplayer is implemented by displays that support partial
+// (dirty-rectangle) refreshes. AppOptions.PartialRefresh has no effect on
+// displays that don't implement it; they always fall back to Draw.
+type PartialDisplayer interface {
+	DrawPartial(buffer []byte) error
+}
+
+// setupDisplay constructs the Display backend selected by --backend.
+func setupDisplay(options AppOptions) (Display, error) {
+	switch options.Backend {
+	case "", "spi":
+		return NewSPIDisplay(options)
+	case "sim":
+		return NewSimDisplay(options)
+	default:
+		return nil, fmt.Errorf("unknown display backend %q", options.Backend)
+	}
+}
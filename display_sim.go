@@ -0,0 +1,116 @@
+package main
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+
+	"github.com/gonutz/framebuffer"
+)
+
+// SimDisplay implements Display without any GPIO/SPI hardware, so the
+// program can be developed and tested off a Raspberry Pi. It writes frames
+// to a Linux framebuffer device when one is available (e.g. a Pi with a
+// local console), and otherwise dumps each frame as a PNG under
+// ~/.trmnl/sim so it can be inspected in an image viewer. Selected with
+// --backend=sim.
+type SimDisplay struct {
+	width, height int
+	fb            *framebuffer.Device
+	dumpDir       string
+	frame         int
+}
+
+// NewSimDisplay prepares the PNG dump directory; Init decides whether a
+// framebuffer device is also available.
+func NewSimDisplay(options AppOptions) (*SimDisplay, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil, fmt.Errorf("error getting home directory for sim dump: %v", err)
+	}
+	dumpDir := filepath.Join(home, ".trmnl", "sim")
+	if err := os.MkdirAll(dumpDir, 0755); err != nil {
+		return nil, fmt.Errorf("error creating sim dump directory: %v", err)
+	}
+
+	return &SimDisplay{width: 800, height: 480, dumpDir: dumpDir}, nil
+}
+
+func (s *SimDisplay) Init() error {
+	fb, err := framebuffer.Open("/dev/fb0")
+	if err != nil {
+		fmt.Printf("No framebuffer available (%v), dumping frames to %s instead\n", err, s.dumpDir)
+		return nil
+	}
+
+	s.fb = fb
+	bounds := fb.Bounds()
+	s.width, s.height = bounds.Dx(), bounds.Dy()
+	fmt.Println("Simulator backend writing to Linux framebuffer")
+	return nil
+}
+
+func (s *SimDisplay) Width() int  { return s.width }
+func (s *SimDisplay) Height() int { return s.height }
+
+func (s *SimDisplay) Clear() error {
+	buffer := make([]byte, s.width*s.height/8)
+	for i := range buffer {
+		buffer[i] = 0xFF // White
+	}
+	return s.Draw(buffer)
+}
+
+func (s *SimDisplay) Draw(buffer []byte) error {
+	img := unpackBuffer(buffer, s.width, s.height)
+
+	if s.fb != nil {
+		for y := 0; y < s.height; y++ {
+			for x := 0; x < s.width; x++ {
+				s.fb.Set(x, y, img.At(x, y))
+			}
+		}
+		return nil
+	}
+
+	s.frame++
+	path := filepath.Join(s.dumpDir, fmt.Sprintf("frame-%04d.png", s.frame))
+	out, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("error creating sim frame dump: %v", err)
+	}
+	defer out.Close()
+	if err := png.Encode(out, img); err != nil {
+		return fmt.Errorf("error encoding sim frame dump: %v", err)
+	}
+	fmt.Printf("Simulator wrote %s\n", path)
+	return nil
+}
+
+func (s *SimDisplay) Sleep() {
+	if s.fb != nil {
+		s.fb.Close()
+	}
+}
+
+// unpackBuffer turns a 1-bit-per-pixel packed buffer (1=white, 0=black, as
+// produced by displayImage) back into a viewable grayscale image.
+func unpackBuffer(buffer []byte, width, height int) *image.Gray {
+	img := image.NewGray(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			bitPos := y*width + x
+			bytePos := bitPos / 8
+			bitOffset := uint(7 - (bitPos % 8))
+			if buffer[bytePos]&(1<<bitOffset) != 0 {
+				img.SetGray(x, y, color.Gray{255})
+			} else {
+				img.SetGray(x, y, color.Gray{0})
+			}
+		}
+	}
+	return img
+}
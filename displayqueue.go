@@ -0,0 +1,29 @@
+package main
+
+// displayJob is one unit of serialized access to the active Display. Both
+// the poll loop and the HTTP control server submit through
+// submitDisplayJob so a frame pushed over HTTP can't land mid-SPI-
+// transaction with a refresh from the other.
+type displayJob struct {
+	fn   func() error
+	done chan error
+}
+
+var displayJobs = make(chan displayJob)
+
+// runDisplayWorker serializes all access to the display package-level
+// variable. It must be started once, before anything calls
+// submitDisplayJob, and runs for the lifetime of the program.
+func runDisplayWorker() {
+	for job := range displayJobs {
+		job.done <- job.fn()
+	}
+}
+
+// submitDisplayJob runs fn on the single display-owning goroutine and waits
+// for it to finish.
+func submitDisplayJob(fn func() error) error {
+	done := make(chan error, 1)
+	displayJobs <- displayJob{fn: fn, done: done}
+	return <-done
+}
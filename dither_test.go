@@ -0,0 +1,86 @@
+package main
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+func solidGray(w, h int, v uint8) *image.Gray {
+	img := image.NewGray(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.SetGray(x, y, color.Gray{v})
+		}
+	}
+	return img
+}
+
+func TestFloydSteinbergDitherSolidColors(t *testing.T) {
+	white := floydSteinbergDither(solidGray(4, 4, 255), false)
+	for _, px := range white.Pix {
+		if px != 255 {
+			t.Fatalf("solid white input produced non-white pixel %#x", px)
+		}
+	}
+
+	black := floydSteinbergDither(solidGray(4, 4, 0), false)
+	for _, px := range black.Pix {
+		if px != 0 {
+			t.Fatalf("solid black input produced non-black pixel %#x", px)
+		}
+	}
+}
+
+func TestFloydSteinbergDitherDarkModeInverts(t *testing.T) {
+	normal := floydSteinbergDither(solidGray(4, 4, 255), false)
+	dark := floydSteinbergDither(solidGray(4, 4, 255), true)
+	for i := range normal.Pix {
+		if normal.Pix[i] == dark.Pix[i] {
+			t.Fatalf("darkMode did not invert pixel %d: both %#x", i, normal.Pix[i])
+		}
+	}
+}
+
+func TestOrderedDitherSolidColors(t *testing.T) {
+	white := orderedDither(solidGray(8, 8, 255), false)
+	for _, px := range white.Pix {
+		if px != 255 {
+			t.Fatalf("solid white input produced non-white pixel %#x", px)
+		}
+	}
+
+	black := orderedDither(solidGray(8, 8, 0), false)
+	for _, px := range black.Pix {
+		if px != 0 {
+			t.Fatalf("solid black input produced non-black pixel %#x", px)
+		}
+	}
+}
+
+func TestOrderedDitherDarkModeInverts(t *testing.T) {
+	normal := orderedDither(solidGray(8, 8, 255), false)
+	dark := orderedDither(solidGray(8, 8, 255), true)
+	for i := range normal.Pix {
+		if normal.Pix[i] == dark.Pix[i] {
+			t.Fatalf("darkMode did not invert pixel %d: both %#x", i, normal.Pix[i])
+		}
+	}
+}
+
+func TestClamp255(t *testing.T) {
+	cases := []struct {
+		in, want float64
+	}{
+		{-10, 0},
+		{0, 0},
+		{128, 128},
+		{255, 255},
+		{300, 255},
+	}
+	for _, c := range cases {
+		if got := clamp255(c.in); got != c.want {
+			t.Errorf("clamp255(%v) = %v, want %v", c.in, got, c.want)
+		}
+	}
+}
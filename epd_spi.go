@@ -0,0 +1,368 @@
+package main
+
+import (
+	"fmt"
+	"image"
+	"time"
+
+	"periph.io/x/conn/v3/gpio"
+	"periph.io/x/conn/v3/gpio/gpioreg"
+	"periph.io/x/conn/v3/physic"
+	"periph.io/x/conn/v3/spi"
+	"periph.io/x/conn/v3/spi/spireg"
+	"periph.io/x/host/v3"
+)
+
+// EPD drives a Waveshare 7.5" e-ink display (V2) over SPI. It implements
+// Display and PartialDisplayer.
+type EPD struct {
+	rstPin  gpio.PinIO
+	dcPin   gpio.PinIO
+	csPin   gpio.PinIO
+	busyPin gpio.PinIO
+	pwrPin  gpio.PinIO
+	spiPort spi.PortCloser
+	conn    spi.Conn
+	width   int
+	height  int
+
+	fullRefreshEvery int
+
+	// prevBuffer holds the last packed-bit frame successfully written to the
+	// panel, used to compute the dirty rectangle for partial refreshes.
+	prevBuffer []byte
+	// partialCount tracks how many partial refreshes have happened since the
+	// last full refresh, so ghosting can be bounded by fullRefreshEvery.
+	partialCount int
+}
+
+// NewSPIDisplay opens the SPI port and GPIO pins for the panel. Call Init
+// afterwards to run the controller's register init sequence.
+func NewSPIDisplay(options AppOptions) (*EPD, error) {
+	if _, err := host.Init(); err != nil {
+		return nil, fmt.Errorf("error initializing periph: %v", err)
+	}
+
+	rstPin := gpioreg.ByName("GPIO17")
+	dcPin := gpioreg.ByName("GPIO25")
+	csPin := gpioreg.ByName("GPIO8")
+	busyPin := gpioreg.ByName("GPIO24")
+	pwrPin := gpioreg.ByName("GPIO18")
+
+	fmt.Printf("RST: %v, DC: %v, CS: %v, BUSY: %v, PWR: %v\n", rstPin, dcPin, csPin, busyPin, pwrPin)
+
+	if rstPin == nil || dcPin == nil || csPin == nil || busyPin == nil || pwrPin == nil {
+		return nil, fmt.Errorf("failed to find GPIO pins")
+	}
+
+	spiPort, err := spireg.Open("/dev/spidev0.0")
+	if err != nil {
+		return nil, fmt.Errorf("error opening SPI: %v", err)
+	}
+	if err := spiPort.LimitSpeed(2 * physic.MegaHertz); err != nil {
+		return nil, fmt.Errorf("error setting SPI speed: %v", err)
+	}
+
+	conn, err := spiPort.Connect(2*physic.MegaHertz, spi.Mode0, 8)
+	if err != nil {
+		return nil, fmt.Errorf("error connecting to SPI: %v", err)
+	}
+
+	return &EPD{
+		rstPin:           rstPin,
+		dcPin:            dcPin,
+		csPin:            csPin,
+		busyPin:          busyPin,
+		pwrPin:           pwrPin,
+		spiPort:          spiPort,
+		conn:             conn,
+		width:            800,
+		height:           480,
+		fullRefreshEvery: options.FullRefreshEvery,
+	}, nil
+}
+
+func (e *EPD) Width() int  { return e.width }
+func (e *EPD) Height() int { return e.height }
+
+func (e *EPD) Init() error {
+	e.pwrPin.Out(gpio.High)
+	time.Sleep(100 * time.Millisecond)
+
+	e.rstPin.Out(gpio.Low)
+	time.Sleep(200 * time.Millisecond)
+	e.rstPin.Out(gpio.High)
+	time.Sleep(200 * time.Millisecond)
+
+	e.sendCommand(0x12) // Soft reset
+	time.Sleep(2 * time.Millisecond)
+	for e.busyPin.Read() == gpio.High {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	e.sendCommand(0x01) // Driver output control
+	e.sendData(0xDF)    // 800-1 = 799 (little-endian: DF 02)
+	e.sendData(0x02)
+	e.sendData(0x00)
+
+	e.sendCommand(0x03) // Gate driving voltage
+	e.sendData(0x00)
+
+	e.sendCommand(0x04) // Source driving voltage
+	e.sendData(0x41)
+	e.sendData(0xA8)
+	e.sendData(0x32)
+
+	e.sendCommand(0x11) // Data entry mode
+	e.sendData(0x03)
+
+	e.sendCommand(0x44) // X address start/end
+	e.sendData(0x00)
+	e.sendData(0x63) // 800/8 - 1 = 99 (0x63)
+
+	e.sendCommand(0x45) // Y address start/end
+	e.sendData(0x00)
+	e.sendData(0x00)
+	e.sendData(0xDF) // 479 (little-endian: DF 01)
+	e.sendData(0x01)
+
+	e.sendCommand(0x4E) // X address counter
+	e.sendData(0x00)
+
+	e.sendCommand(0x4F) // Y address counter
+	e.sendData(0x00)
+	e.sendData(0x00)
+
+	fmt.Println("Waveshare 7.5\" e-ink display (V2) initialized successfully")
+	return nil
+}
+
+func (e *EPD) sendCommand(cmd byte) {
+	e.dcPin.Out(gpio.Low)
+	if e.conn == nil {
+		panic("SPI connection is nil")
+	}
+	e.conn.Tx([]byte{cmd}, nil)
+}
+
+func (e *EPD) sendData(data byte) {
+	e.dcPin.Out(gpio.High)
+	if e.conn == nil {
+		panic("SPI connection is nil")
+	}
+	e.conn.Tx([]byte{data}, nil)
+}
+
+func (e *EPD) sendData2(buffer []byte) error {
+	const maxTxSize = 4096
+	e.dcPin.Out(gpio.High)
+	if e.conn == nil {
+		return fmt.Errorf("SPI connection is nil")
+	}
+	for i := 0; i < len(buffer); i += maxTxSize {
+		end := i + maxTxSize
+		if end > len(buffer) {
+			end = len(buffer)
+		}
+		chunk := buffer[i:end]
+		err := e.conn.Tx(chunk, nil)
+		if err != nil {
+			return fmt.Errorf("error sending buffer chunk %d-%d: %v", i, end, err)
+		}
+	}
+	return nil
+}
+
+func (e *EPD) Sleep() {
+	e.sendCommand(0x10) // Deep sleep
+	e.sendData(0x01)
+	time.Sleep(200 * time.Millisecond)
+	e.pwrPin.Out(gpio.Low)
+	e.spiPort.Close()
+	fmt.Println("Waveshare 7.5\" e-ink display put to sleep")
+}
+
+func (e *EPD) Clear() error {
+	buffer := make([]byte, e.width*e.height/8)
+	for i := range buffer {
+		buffer[i] = 0xFF // White
+	}
+	return e.Draw(buffer)
+}
+
+func (e *EPD) Draw(buffer []byte) error {
+	// A prior DrawPartial call may have shrunk the RAM window/address
+	// counters to its dirty rect via setPartialWindow; restore them to the
+	// full panel before streaming a full-size buffer, or the controller
+	// wraps the write into that leftover window instead of the whole panel.
+	e.setPartialWindow(0, 0, e.width, e.height)
+
+	// Create inverted buffer (image1)
+	image1 := make([]byte, len(buffer))
+	for i := range buffer {
+		image1[i] = ^buffer[i] // Bitwise NOT
+	}
+
+	// Send old data (inverted)
+	e.sendCommand(0x10)
+	err := e.sendData2(image1)
+	if err != nil {
+		return fmt.Errorf("error sending old data: %v", err)
+	}
+
+	// Send new data
+	e.sendCommand(0x13)
+	err = e.sendData2(buffer)
+	if err != nil {
+		return fmt.Errorf("error sending new data: %v", err)
+	}
+
+	// Refresh
+	e.sendCommand(0x12)
+	time.Sleep(100 * time.Millisecond)
+	for e.busyPin.Read() == gpio.High {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	e.prevBuffer = buffer
+	e.partialCount = 0
+
+	return nil
+}
+
+// DrawPartial refreshes only the rectangle that changed since the last
+// Draw/DrawPartial call, falling back to a full Draw when there's no
+// previous frame to diff against or fullRefreshEvery partials have
+// accumulated (partial refreshes leave faint ghosting over time). It
+// satisfies PartialDisplayer for callers that only have a Display.
+func (e *EPD) DrawPartial(buffer []byte) error {
+	if e.prevBuffer == nil || e.partialCount >= e.fullRefreshEvery {
+		return e.Draw(buffer)
+	}
+
+	rect := computeDirtyRect(e.prevBuffer, buffer, e.width, e.height)
+	if rect.Empty() {
+		fmt.Println("No changes detected, skipping refresh")
+		return nil
+	}
+
+	return e.DisplayPartial(buffer, rect)
+}
+
+// DisplayPartial refreshes only rect, leaving the rest of the panel
+// untouched, using the Waveshare partial-update command sequence. Callers
+// that already know which rect changed (rather than going through
+// DrawPartial's own diffing) can call this directly.
+func (e *EPD) DisplayPartial(buffer []byte, rect image.Rectangle) error {
+	byteWidth := e.width / 8
+
+	x0 := (rect.Min.X / 8) * 8
+	x1 := ((rect.Max.X + 7) / 8) * 8
+	if x1 > e.width {
+		x1 = e.width
+	}
+	y0 := rect.Min.Y
+	y1 := rect.Max.Y
+	if y1 > e.height {
+		y1 = e.height
+	}
+
+	e.setPartialWindow(x0, y0, x1, y1)
+
+	prevWindow := extractWindow(e.prevBuffer, byteWidth, x0, y0, x1, y1)
+	for i := range prevWindow {
+		prevWindow[i] = ^prevWindow[i]
+	}
+	newWindow := extractWindow(buffer, byteWidth, x0, y0, x1, y1)
+
+	e.sendCommand(0x26) // Load previous image into old-data RAM
+	if err := e.sendData2(prevWindow); err != nil {
+		return fmt.Errorf("error sending previous window data: %v", err)
+	}
+
+	e.sendCommand(0x24) // Load new image into RAM
+	if err := e.sendData2(newWindow); err != nil {
+		return fmt.Errorf("error sending new window data: %v", err)
+	}
+
+	e.sendCommand(0x22) // Display update control 2
+	e.sendData(0xFF)    // Enable clock/analog, load LUT, partial refresh
+	e.sendCommand(0x20) // Activate display update sequence
+	time.Sleep(10 * time.Millisecond)
+	for e.busyPin.Read() == gpio.High {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	e.prevBuffer = buffer
+	e.partialCount++
+
+	return nil
+}
+
+// setPartialWindow restricts the active RAM window to [x0,x1)x[y0,y1), in
+// the same X/Y address registers Init uses for the full panel.
+func (e *EPD) setPartialWindow(x0, y0, x1, y1 int) {
+	e.sendCommand(0x44) // X address start/end
+	e.sendData(byte(x0 / 8))
+	e.sendData(byte(x1/8 - 1))
+
+	e.sendCommand(0x45) // Y address start/end
+	e.sendData(byte(y0 & 0xFF))
+	e.sendData(byte(y0 >> 8))
+	e.sendData(byte((y1 - 1) & 0xFF))
+	e.sendData(byte((y1 - 1) >> 8))
+
+	e.sendCommand(0x4E) // X address counter
+	e.sendData(byte(x0 / 8))
+
+	e.sendCommand(0x4F) // Y address counter
+	e.sendData(byte(y0 & 0xFF))
+	e.sendData(byte(y0 >> 8))
+}
+
+// extractWindow copies the packed-bit rows [y0,y1) and byte-columns
+// [x0/8,x1/8) out of a full-panel buffer with the given byte width per row.
+func extractWindow(buffer []byte, byteWidth, x0, y0, x1, y1 int) []byte {
+	winByteWidth := (x1 - x0) / 8
+	out := make([]byte, winByteWidth*(y1-y0))
+	for row := y0; row < y1; row++ {
+		srcStart := row*byteWidth + x0/8
+		copy(out[(row-y0)*winByteWidth:(row-y0+1)*winByteWidth], buffer[srcStart:srcStart+winByteWidth])
+	}
+	return out
+}
+
+// computeDirtyRect XORs prev and cur bit for bit and returns the bounding
+// box of pixels that changed. It returns the zero Rectangle when the two
+// buffers are identical.
+func computeDirtyRect(prev, cur []byte, width, height int) image.Rectangle {
+	minX, minY := width, height
+	maxX, maxY := -1, -1
+
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			bitPos := y*width + x
+			bytePos := bitPos / 8
+			bitOffset := uint(7 - (bitPos % 8))
+			if (prev[bytePos]^cur[bytePos])&(1<<bitOffset) != 0 {
+				if x < minX {
+					minX = x
+				}
+				if x > maxX {
+					maxX = x
+				}
+				if y < minY {
+					minY = y
+				}
+				if y > maxY {
+					maxY = y
+				}
+			}
+		}
+	}
+
+	if maxX < minX {
+		return image.Rectangle{}
+	}
+	return image.Rect(minX, minY, maxX+1, maxY+1)
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"image"
+	"testing"
+)
+
+func TestComputeDirtyRectNoChange(t *testing.T) {
+	buf := make([]byte, 10*8/8)
+	for i := range buf {
+		buf[i] = 0xFF
+	}
+	cur := make([]byte, len(buf))
+	copy(cur, buf)
+
+	rect := computeDirtyRect(buf, cur, 10, 8)
+	if !rect.Empty() {
+		t.Fatalf("expected empty rect for identical buffers, got %v", rect)
+	}
+}
+
+func TestComputeDirtyRectBoundsChangedBit(t *testing.T) {
+	const width, height = 16, 4
+	prev := make([]byte, width*height/8)
+	for i := range prev {
+		prev[i] = 0xFF
+	}
+	cur := make([]byte, len(prev))
+	copy(cur, prev)
+
+	// Flip the bit for pixel (x=10, y=2).
+	bitPos := 2*width + 10
+	cur[bitPos/8] ^= 1 << uint(7-bitPos%8)
+
+	rect := computeDirtyRect(prev, cur, width, height)
+	want := image.Rect(10, 2, 11, 3)
+	if rect != want {
+		t.Fatalf("got rect %v, want %v", rect, want)
+	}
+}
+
+func TestExtractWindow(t *testing.T) {
+	const byteWidth = 4 // 32px wide, 1bpp
+	buffer := []byte{
+		0x11, 0x22, 0x33, 0x44,
+		0x55, 0x66, 0x77, 0x88,
+		0x99, 0xAA, 0xBB, 0xCC,
+	}
+
+	got := extractWindow(buffer, byteWidth, 8, 1, 24, 3)
+	want := []byte{0x66, 0x77, 0xAA, 0xBB}
+
+	if len(got) != len(want) {
+		t.Fatalf("got %d bytes, want %d: %x", len(got), len(want), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("byte %d: got %#x, want %#x", i, got[i], want[i])
+		}
+	}
+}
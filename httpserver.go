@@ -0,0 +1,123 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// ControlServer exposes a small local HTTP API so other programs (home
+// automation, cron jobs, scripts) can push frames to the panel or trigger a
+// refresh independent of the TRMNL cloud. Started when --listen is set.
+type ControlServer struct {
+	tmpDir  string
+	apiKey  string
+	options AppOptions
+
+	mu          sync.Mutex
+	lastRefresh time.Time
+	refreshRate int
+}
+
+func newControlServer(tmpDir, apiKey string, options AppOptions) *ControlServer {
+	return &ControlServer{tmpDir: tmpDir, apiKey: apiKey, options: options, refreshRate: 60}
+}
+
+// noteRefresh records that a frame was just fetched and displayed, for
+// GET /status.
+func (c *ControlServer) noteRefresh(refreshRate int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.lastRefresh = time.Now()
+	if refreshRate > 0 {
+		c.refreshRate = refreshRate
+	}
+}
+
+// ListenAndServe blocks serving the control API on addr.
+func (c *ControlServer) ListenAndServe(addr string) error {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /image", c.handleImage)
+	mux.HandleFunc("POST /refresh", c.handleRefresh)
+	mux.HandleFunc("POST /clear", c.handleClear)
+	mux.HandleFunc("POST /sleep", c.handleSleep)
+	mux.HandleFunc("GET /status", c.handleStatus)
+
+	fmt.Printf("Control server listening on %s\n", addr)
+	return http.ListenAndServe(addr, mux)
+}
+
+// handleImage renders a PNG/JPEG body immediately, the same way an image
+// fetched from the TRMNL API would be.
+func (c *ControlServer) handleImage(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	filePath := filepath.Join(c.tmpDir, fmt.Sprintf("push-%d.img", time.Now().UnixNano()))
+	out, err := os.Create(filePath)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("error creating temp file: %v", err), http.StatusInternalServerError)
+		return
+	}
+	defer os.Remove(filePath)
+
+	if _, err := io.Copy(out, r.Body); err != nil {
+		out.Close()
+		http.Error(w, fmt.Sprintf("error reading request body: %v", err), http.StatusBadRequest)
+		return
+	}
+	out.Close()
+
+	if err := displayImage(filePath, c.options); err != nil {
+		http.Error(w, fmt.Sprintf("error displaying image: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	c.noteRefresh(0)
+	w.WriteHeader(http.StatusOK)
+}
+
+// handleRefresh wakes the poll loop so it re-fetches from TRMNL right away
+// instead of waiting out the rest of the current refresh interval.
+func (c *ControlServer) handleRefresh(w http.ResponseWriter, r *http.Request) {
+	select {
+	case refreshNow <- struct{}{}:
+	default: // a refresh is already pending
+	}
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func (c *ControlServer) handleClear(w http.ResponseWriter, r *http.Request) {
+	clearDisplay()
+	w.WriteHeader(http.StatusOK)
+}
+
+// handleSleep puts the panel into deep sleep and pauses the poll loop, since
+// there's nothing useful to draw until the panel is reset. Waking it back up
+// requires a full Init, same as at program start, since the panel's deep
+// sleep mode only exits on reset.
+func (c *ControlServer) handleSleep(w http.ResponseWriter, r *http.Request) {
+	sleepDisplay()
+	w.WriteHeader(http.StatusOK)
+}
+
+func (c *ControlServer) handleStatus(w http.ResponseWriter, r *http.Request) {
+	c.mu.Lock()
+	status := struct {
+		LastRefresh time.Time `json:"last_refresh"`
+		RefreshRate int       `json:"refresh_rate"`
+		Version     string    `json:"version"`
+	}{
+		LastRefresh: c.lastRefresh,
+		RefreshRate: c.refreshRate,
+		Version:     version,
+	}
+	c.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(status)
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// lastFramePath returns ~/.trmnl/last_frame.bin, where the last
+// successfully drawn packed-bit buffer is persisted across restarts so the
+// panel doesn't need to be cleared and re-tested on every boot, and so an
+// unchanged frame can skip the SPI transaction entirely.
+func lastFramePath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("error getting home directory: %v", err)
+	}
+	return filepath.Join(home, ".trmnl", "last_frame.bin"), nil
+}
+
+// loadLastFrame returns the persisted buffer, or nil if none has been saved
+// yet.
+func loadLastFrame() ([]byte, error) {
+	path, err := lastFramePath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading last frame: %v", err)
+	}
+	return data, nil
+}
+
+// saveLastFrame persists buffer so it survives a restart.
+func saveLastFrame(buffer []byte) error {
+	path, err := lastFramePath()
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, buffer, 0600); err != nil {
+		return fmt.Errorf("error writing last frame: %v", err)
+	}
+	return nil
+}
+
+// bufferHash returns the SHA-256 of a packed-bit frame; renderImage compares
+// the hash of each new frame with that of the persisted one.
+func bufferHash(buffer []byte) [32]byte {
+	return sha256.Sum256(buffer)
+}
+
+// clearLastFrame removes the persisted frame, if any. Call it whenever the
+// panel is cleared outside the normal displayImage path (boot, POST /clear)
+// so the next frame isn't mistaken for a no-op repeat of whatever was shown
+// before the clear.
+func clearLastFrame() error {
+	path, err := lastFramePath()
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("error clearing persisted frame: %v", err)
+	}
+	return nil
+}
@@ -9,21 +9,23 @@ import (
 	"io"
 	"net/http"
 	"os"
-	"os/exec"
 	"path/filepath"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/disintegration/imaging"
-	"periph.io/x/conn/v3/gpio"
-	"periph.io/x/conn/v3/gpio/gpioreg"
-	"periph.io/x/conn/v3/physic"
-	"periph.io/x/conn/v3/spi"
-	"periph.io/x/conn/v3/spi/spireg"
-	"periph.io/x/host/v3"
+	"golang.org/x/image/bmp"
+	"golang.org/x/image/webp"
 	_ "image/jpeg"
 	_ "image/png"
 )
 
+func init() {
+	image.RegisterFormat("bmp", "BM", bmp.Decode, bmp.DecodeConfig)
+	image.RegisterFormat("webp", "RIFF????WEBP", webp.Decode, webp.DecodeConfig)
+}
+
 // Version information
 var (
 	version   = "0.1.0"
@@ -45,36 +47,79 @@ type Config struct {
 
 // AppOptions holds command line options
 type AppOptions struct {
-	DarkMode bool
-	Verbose  bool
+	DarkMode         bool
+	Verbose          bool
+	PartialRefresh   bool
+	FullRefreshEvery int
+	// Dither selects the grayscale-to-monochrome conversion: "" for the
+	// plain threshold, "fs" for Floyd-Steinberg error diffusion, or
+	// "ordered" for an 8x8 Bayer matrix.
+	Dither string
+	// Backend selects the Display implementation: "spi" (default, the real
+	// Waveshare panel) or "sim" (framebuffer/PNG dump, for development).
+	Backend string
+	// Listen, if non-empty, starts a local HTTP control server (e.g.
+	// ":8080") so other programs can push frames or trigger a refresh
+	// independent of the TRMNL poll loop.
+	Listen string
 }
 
-// EPD holds the display configuration
-type EPD struct {
-	rstPin  gpio.PinIO
-	dcPin   gpio.PinIO
-	csPin   gpio.PinIO
-	busyPin gpio.PinIO
-	pwrPin  gpio.PinIO
-	spiPort spi.PortCloser
-	conn    spi.Conn
-	Width   int
-	Height  int
+var display Display
+
+// controlServer is non-nil when --listen is set; processNextImage reports
+// its refresh activity to it for GET /status.
+var controlServer *ControlServer
+
+// refreshNow lets the HTTP control server's POST /refresh interrupt the
+// poll loop's wait early instead of waiting out the full refresh interval.
+var refreshNow = make(chan struct{}, 1)
+
+// sleepOrRefresh waits for d, or returns early if a refresh was requested
+// through refreshNow.
+func sleepOrRefresh(d time.Duration) {
+	select {
+	case <-time.After(d):
+	case <-refreshNow:
+	}
 }
 
-var (
-	epd *EPD
-)
+// sleepOnce guards display.Sleep(), which closes the SPI port: POST /sleep
+// and the program-exit defer in main can both reach sleepDisplay, and a
+// second close would panic.
+var sleepOnce sync.Once
+
+// displaySleeping is set once the panel has been put to sleep, so
+// processNextImage stops fetching and drawing against a closed SPI port
+// instead of spinning errors until the process exits.
+var displaySleeping atomic.Bool
+
+// sleepDisplay puts the panel into deep sleep exactly once.
+func sleepDisplay() {
+	sleepOnce.Do(func() {
+		displaySleeping.Store(true)
+		submitDisplayJob(func() error {
+			display.Sleep()
+			return nil
+		})
+	})
+}
 
 func main() {
 	options := parseCommandLineArgs()
 
-	err := initDisplay()
+	d, err := setupDisplay(options)
 	if err != nil {
+		fmt.Printf("Error setting up display backend: %v\n", err)
+		os.Exit(1)
+	}
+	display = d
+
+	if err := display.Init(); err != nil {
 		fmt.Printf("Error initializing e-ink display: %v\n", err)
 		os.Exit(1)
 	}
-	defer cleanupDisplay()
+	defer sleepDisplay()
+	go runDisplayWorker()
 
 	configDir, err := os.UserHomeDir()
 	if err != nil {
@@ -106,238 +151,74 @@ func main() {
 	}
 	defer os.RemoveAll(tmpDir)
 
-	clearDisplay()
-	testDisplay()
-
-	for {
-		processNextImage(tmpDir, config.APIKey, options)
-	}
-}
-
-func initDisplay() error {
-	if _, err := host.Init(); err != nil {
-		return fmt.Errorf("error initializing periph: %v", err)
-	}
-
-	rstPin := gpioreg.ByName("GPIO17")
-	dcPin := gpioreg.ByName("GPIO25")
-	csPin := gpioreg.ByName("GPIO8")
-	busyPin := gpioreg.ByName("GPIO24")
-	pwrPin := gpioreg.ByName("GPIO18")
-
-	fmt.Printf("RST: %v, DC: %v, CS: %v, BUSY: %v, PWR: %v\n", rstPin, dcPin, csPin, busyPin, pwrPin)
-
-	if rstPin == nil || dcPin == nil || csPin == nil || busyPin == nil || pwrPin == nil {
-		return fmt.Errorf("failed to find GPIO pins")
-	}
-
-	spiPort, err := spireg.Open("/dev/spidev0.0")
-	if err != nil {
-		return fmt.Errorf("error opening SPI: %v", err)
-	}
-	if err := spiPort.LimitSpeed(2 * physic.MegaHertz); err != nil {
-		return fmt.Errorf("error setting SPI speed: %v", err)
-	}
-
-	conn, err := spiPort.Connect(2*physic.MegaHertz, spi.Mode0, 8)
-	if err != nil {
-		return fmt.Errorf("error connecting to SPI: %v", err)
-	}
-
-	epd = &EPD{
-		rstPin:  rstPin,
-		dcPin:   dcPin,
-		csPin:   csPin,
-		busyPin: busyPin,
-		pwrPin:  pwrPin,
-		spiPort: spiPort,
-		conn:    conn,
-		Width:   800,
-		Height:  480,
-	}
-
-	err = epd.init()
-	if err != nil {
-		return fmt.Errorf("error initializing EPD: %v", err)
-	}
-	fmt.Println("Waveshare 7.5\" e-ink display (V2) initialized successfully")
-	return nil
-}
-
-func (e *EPD) init() error {
-	e.pwrPin.Out(gpio.High)
-	time.Sleep(100 * time.Millisecond)
-
-	e.rstPin.Out(gpio.Low)
-	time.Sleep(200 * time.Millisecond)
-	e.rstPin.Out(gpio.High)
-	time.Sleep(200 * time.Millisecond)
-
-	e.sendCommand(0x12) // Soft reset
-	time.Sleep(2 * time.Millisecond)
-	for e.busyPin.Read() == gpio.High {
-		time.Sleep(10 * time.Millisecond)
-	}
-
-	e.sendCommand(0x01) // Driver output control
-	e.sendData(0xDF)    // 800-1 = 799 (little-endian: DF 02)
-	e.sendData(0x02)
-	e.sendData(0x00)
-
-	e.sendCommand(0x03) // Gate driving voltage
-	e.sendData(0x00)
-
-	e.sendCommand(0x04) // Source driving voltage
-	e.sendData(0x41)
-	e.sendData(0xA8)
-	e.sendData(0x32)
-
-	e.sendCommand(0x11) // Data entry mode
-	e.sendData(0x03)
-
-	e.sendCommand(0x44) // X address start/end
-	e.sendData(0x00)
-	e.sendData(0x63) // 800/8 - 1 = 99 (0x63)
-
-	e.sendCommand(0x45) // Y address start/end
-	e.sendData(0x00)
-	e.sendData(0x00)
-	e.sendData(0xDF) // 479 (little-endian: DF 01)
-	e.sendData(0x01)
-
-	e.sendCommand(0x4E) // X address counter
-	e.sendData(0x00)
-
-	e.sendCommand(0x4F) // Y address counter
-	e.sendData(0x00)
-	e.sendData(0x00)
-
-	return nil
-}
-
-func (e *EPD) sendCommand(cmd byte) {
-	e.dcPin.Out(gpio.Low)
-	if e.conn == nil {
-		panic("SPI connection is nil")
-	}
-	e.conn.Tx([]byte{cmd}, nil)
-}
-
-func (e *EPD) sendData(data byte) {
-	e.dcPin.Out(gpio.High)
-	if e.conn == nil {
-		panic("SPI connection is nil")
+	if options.Listen != "" {
+		controlServer = newControlServer(tmpDir, config.APIKey, options)
+		go func() {
+			if err := controlServer.ListenAndServe(options.Listen); err != nil {
+				fmt.Printf("Error running control server: %v\n", err)
+			}
+		}()
 	}
-	e.conn.Tx([]byte{data}, nil)
-}
 
-func (e *EPD) sendData2(buffer []byte) error {
-	const maxTxSize = 4096
-	e.dcPin.Out(gpio.High)
-	if e.conn == nil {
-		return fmt.Errorf("SPI connection is nil")
-	}
-	for i := 0; i < len(buffer); i += maxTxSize {
-		end := i + maxTxSize
-		if end > len(buffer) {
-			end = len(buffer)
-		}
-		chunk := buffer[i:end]
-		err := e.conn.Tx(chunk, nil)
-		if err != nil {
-			return fmt.Errorf("error sending buffer chunk %d-%d: %v", i, end, err)
+	if framePath, err := lastFramePath(); err == nil {
+		if _, err := os.Stat(framePath); err != nil {
+			clearDisplay()
+			testDisplay()
 		}
+	} else {
+		clearDisplay()
+		testDisplay()
 	}
-	return nil
-}
 
-func cleanupDisplay() {
-	if epd != nil {
-		epd.sleep()
-		epd.spiPort.Close()
-		fmt.Println("Waveshare 7.5\" e-ink display put to sleep")
+	for {
+		processNextImage(tmpDir, config.APIKey, options)
 	}
 }
 
-func (e *EPD) sleep() {
-	e.sendCommand(0x10) // Deep sleep
-	e.sendData(0x01)
-	time.Sleep(200 * time.Millisecond)
-	e.pwrPin.Out(gpio.Low)
-}
-
 func clearDisplay() {
 	fmt.Println("Clearing e-ink display...")
-	buffer := make([]byte, 800*480/8)
-	for i := range buffer {
-		buffer[i] = 0xFF // White
-	}
-	err := epd.display(buffer)
+	err := submitDisplayJob(func() error { return display.Clear() })
 	if err != nil {
 		fmt.Printf("Error clearing display: %v\n", err)
+	} else if err := clearLastFrame(); err != nil {
+		fmt.Printf("Error invalidating persisted frame: %v\n", err)
 	}
 	time.Sleep(2 * time.Second)
 }
 
 func testDisplay() {
 	fmt.Println("Testing display with pattern...")
-	buffer := make([]byte, 800*480/8)
+	buffer := make([]byte, display.Width()*display.Height()/8)
 	for i := 0; i < len(buffer)/2; i++ {
 		buffer[i] = 0x00 // Black
 	}
 	for i := len(buffer)/2; i < len(buffer); i++ {
 		buffer[i] = 0xFF // White
 	}
-	err := epd.display(buffer)
+	err := submitDisplayJob(func() error { return display.Draw(buffer) })
 	if err != nil {
 		fmt.Printf("Error testing display: %v\n", err)
 	}
 	time.Sleep(2 * time.Second)
 }
 
-func (e *EPD) display(buffer []byte) error {
-	// Create inverted buffer (image1)
-	image1 := make([]byte, len(buffer))
-	for i := range buffer {
-		image1[i] = ^buffer[i] // Bitwise NOT
-	}
-
-	// Send old data (inverted)
-	e.sendCommand(0x10)
-	err := e.sendData2(image1)
-	if err != nil {
-		return fmt.Errorf("error sending old data: %v", err)
-	}
-
-	// Send new data
-	e.sendCommand(0x13)
-	err = e.sendData2(buffer)
-	if err != nil {
-		return fmt.Errorf("error sending new data: %v", err)
-	}
-
-	// Refresh
-	e.sendCommand(0x12)
-	time.Sleep(100 * time.Millisecond)
-	for e.busyPin.Read() == gpio.High {
-		time.Sleep(10 * time.Millisecond)
-	}
-
-	return nil
-}
-
 func processNextImage(tmpDir, apiKey string, options AppOptions) {
 	defer func() {
 		if r := recover(); r != nil {
 			fmt.Printf("Recovered from panic: %v\n", r)
-			time.Sleep(60 * time.Second)
+			sleepOrRefresh(60 * time.Second)
 		}
 	}()
 
+	if displaySleeping.Load() {
+		sleepOrRefresh(60 * time.Second)
+		return
+	}
+
 	req, err := http.NewRequest("GET", "https://usetrmnl.com/api/display", nil)
 	if err != nil {
 		fmt.Printf("Error creating request: %v\n", err)
-		time.Sleep(60 * time.Second)
+		sleepOrRefresh(60 * time.Second)
 		return
 	}
 
@@ -347,14 +228,14 @@ func processNextImage(tmpDir, apiKey string, options AppOptions) {
 	resp, err := client.Do(req)
 	if err != nil {
 		fmt.Printf("Error fetching display: %v\n", err)
-		time.Sleep(60 * time.Second)
+		sleepOrRefresh(60 * time.Second)
 		return
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != 200 {
 		fmt.Printf("Error fetching display: status code %d\n", resp.StatusCode)
-		time.Sleep(60 * time.Second)
+		sleepOrRefresh(60 * time.Second)
 		return
 	}
 
@@ -362,7 +243,7 @@ func processNextImage(tmpDir, apiKey string, options AppOptions) {
 	decoder := json.NewDecoder(resp.Body)
 	if err := decoder.Decode(&terminal); err != nil {
 		fmt.Printf("Error parsing JSON: %v\n", err)
-		time.Sleep(60 * time.Second)
+		sleepOrRefresh(60 * time.Second)
 		return
 	}
 
@@ -375,7 +256,7 @@ func processNextImage(tmpDir, apiKey string, options AppOptions) {
 	imgResp, err := http.Get(terminal.ImageURL)
 	if err != nil {
 		fmt.Printf("Error downloading image: %v\n", err)
-		time.Sleep(60 * time.Second)
+		sleepOrRefresh(60 * time.Second)
 		return
 	}
 	defer imgResp.Body.Close()
@@ -383,14 +264,14 @@ func processNextImage(tmpDir, apiKey string, options AppOptions) {
 	out, err := os.Create(filePath)
 	if err != nil {
 		fmt.Printf("Error creating file: %v\n", err)
-		time.Sleep(60 * time.Second)
+		sleepOrRefresh(60 * time.Second)
 		return
 	}
 	_, err = io.Copy(out, imgResp.Body)
 	if err != nil {
 		fmt.Printf("Error saving image: %v\n", err)
 		out.Close()
-		time.Sleep(60 * time.Second)
+		sleepOrRefresh(60 * time.Second)
 		return
 	}
 	out.Close()
@@ -398,7 +279,7 @@ func processNextImage(tmpDir, apiKey string, options AppOptions) {
 	err = displayImage(filePath, options)
 	if err != nil {
 		fmt.Printf("Error displaying image: %v\n", err)
-		time.Sleep(60 * time.Second)
+		sleepOrRefresh(60 * time.Second)
 		return
 	}
 
@@ -406,47 +287,29 @@ func processNextImage(tmpDir, apiKey string, options AppOptions) {
 	if refreshRate <= 0 {
 		refreshRate = 60
 	}
-	time.Sleep(time.Duration(refreshRate) * time.Second)
+	if controlServer != nil {
+		controlServer.noteRefresh(refreshRate)
+	}
+	sleepOrRefresh(time.Duration(refreshRate) * time.Second)
 }
 
+// displayImage renders imagePath and draws it, serialized through the
+// display queue so a TRMNL poll and an HTTP POST /image can't interleave
+// their reads and writes of debug_buffer.png and last_frame.bin.
 func displayImage(imagePath string, options AppOptions) error {
+	return submitDisplayJob(func() error { return renderImage(imagePath, options) })
+}
+
+// renderImage does the actual decode/dither/draw/persist work. It must only
+// be called from within a displayJob (i.e. via displayImage), since it
+// calls display.Draw/DrawPartial directly rather than through
+// submitDisplayJob, to avoid deadlocking the single display worker.
+func renderImage(imagePath string, options AppOptions) error {
 	if options.Verbose {
 		fmt.Printf("Reading image from %s\n", imagePath)
 	}
 
-	file, err := os.Open(imagePath)
-	if err != nil {
-		return fmt.Errorf("error opening image file for detection: %v", err)
-	}
-	defer file.Close()
-
-	buffer := make([]byte, 512)
-	_, err = file.Read(buffer)
-	if err != nil && err != io.EOF {
-		return fmt.Errorf("error reading image for detection: %v", err)
-	}
-	contentType := http.DetectContentType(buffer)
-
-	_, err = file.Seek(0, 0)
-	if err != nil {
-		return fmt.Errorf("error resetting file pointer: %v", err)
-	}
-
-	var imgPath string
-	if contentType == "image/bmp" {
-		pngPath := imagePath + ".png"
-		cmd := exec.Command("convert", imagePath, pngPath)
-		err := cmd.Run()
-		if err != nil {
-			return fmt.Errorf("error converting BMP to PNG with convert: %v", err)
-		}
-		defer os.Remove(pngPath)
-		imgPath = pngPath
-	} else {
-		imgPath = imagePath
-	}
-
-	imgFile, err := os.Open(imgPath)
+	imgFile, err := os.Open(imagePath)
 	if err != nil {
 		return fmt.Errorf("error opening image file: %v", err)
 	}
@@ -457,36 +320,44 @@ func displayImage(imagePath string, options AppOptions) error {
 		return fmt.Errorf("error decoding image: %v", err)
 	}
 
-	resizedImg := imaging.Resize(img, epd.Width, epd.Height, imaging.NearestNeighbor)
-
-	monoImg := image.NewGray(resizedImg.Bounds())
-	threshold := uint8(128)
-	for y := 0; y < resizedImg.Bounds().Dy(); y++ {
-		for x := 0; x < resizedImg.Bounds().Dx(); x++ {
-			r, g, b, _ := resizedImg.At(x, y).RGBA()
-			gray := uint8((r*299 + g*587 + b*114) / 1000 >> 8)
-			if options.DarkMode {
-				if gray < threshold {
-					monoImg.SetGray(x, y, color.Gray{255}) // White
-				} else {
-					monoImg.SetGray(x, y, color.Gray{0})   // Black
-				}
-			} else {
-				if gray < threshold {
-					monoImg.SetGray(x, y, color.Gray{0})   // Black
+	resizedImg := imaging.Resize(img, display.Width(), display.Height(), imaging.NearestNeighbor)
+
+	var monoImg *image.Gray
+	switch options.Dither {
+	case "fs":
+		monoImg = floydSteinbergDither(resizedImg, options.DarkMode)
+	case "ordered":
+		monoImg = orderedDither(resizedImg, options.DarkMode)
+	default:
+		monoImg = image.NewGray(resizedImg.Bounds())
+		threshold := uint8(128)
+		for y := 0; y < resizedImg.Bounds().Dy(); y++ {
+			for x := 0; x < resizedImg.Bounds().Dx(); x++ {
+				r, g, b, _ := resizedImg.At(x, y).RGBA()
+				gray := uint8((r*299 + g*587 + b*114) / 1000 >> 8)
+				if options.DarkMode {
+					if gray < threshold {
+						monoImg.SetGray(x, y, color.Gray{255}) // White
+					} else {
+						monoImg.SetGray(x, y, color.Gray{0})   // Black
+					}
 				} else {
-					monoImg.SetGray(x, y, color.Gray{255}) // White
+					if gray < threshold {
+						monoImg.SetGray(x, y, color.Gray{0})   // Black
+					} else {
+						monoImg.SetGray(x, y, color.Gray{255}) // White
+					}
 				}
 			}
 		}
 	}
 
 	// Convert to buffer (Black=0, White=1)
-	buffer := make([]byte, epd.Width*epd.Height/8)
-	for y := 0; y < epd.Height; y++ {
-		for x := 0; x < epd.Width; x++ {
+	buffer := make([]byte, display.Width()*display.Height()/8)
+	for y := 0; y < display.Height(); y++ {
+		for x := 0; x < display.Width(); x++ {
 			gray := monoImg.GrayAt(x, y).Y
-			bitPos := y*epd.Width + x
+			bitPos := y*display.Width() + x
 			bytePos := bitPos / 8
 			bitOffset := uint(7 - (bitPos % 8))
 			if gray == 0 { // Black
@@ -505,22 +376,155 @@ func displayImage(imagePath string, options AppOptions) error {
 		fmt.Println("Saved debug_buffer.png for inspection")
 	}
 
-	err = epd.display(buffer)
+	lastBuffer, err := loadLastFrame()
+	if err != nil && options.Verbose {
+		fmt.Printf("Warning: error reading persisted frame: %v\n", err)
+	}
+	if lastBuffer != nil && bufferHash(lastBuffer) == bufferHash(buffer) {
+		if options.Verbose {
+			fmt.Println("Frame unchanged since last refresh, skipping display update")
+		}
+		return nil
+	}
+
+	if pd, ok := display.(PartialDisplayer); ok && options.PartialRefresh {
+		err = pd.DrawPartial(buffer)
+	} else {
+		err = display.Draw(buffer)
+	}
 	if err != nil {
 		return fmt.Errorf("error displaying buffer: %v", err)
 	}
 
+	if err := saveLastFrame(buffer); err != nil && options.Verbose {
+		fmt.Printf("Warning: error persisting frame: %v\n", err)
+	}
+
 	if options.Verbose {
 		fmt.Println("Image displayed on Waveshare 7.5\" e-ink display")
 	}
 	return nil
 }
 
+// floydSteinbergDither converts img to black/white using Floyd-Steinberg
+// error diffusion instead of a hard threshold, which preserves detail in
+// photos and gradients that the TRMNL server renders. darkMode swaps which
+// polarity counts as "on", matching the plain threshold path.
+func floydSteinbergDither(img image.Image, darkMode bool) *image.Gray {
+	bounds := img.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+
+	gray := make([]float64, w*h)
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			r, g, b, _ := img.At(bounds.Min.X+x, bounds.Min.Y+y).RGBA()
+			gray[y*w+x] = float64((r*299 + g*587 + b*114) / 1000 >> 8)
+		}
+	}
+
+	out := image.NewGray(bounds)
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			old := gray[y*w+x]
+			quantized := 0.0
+			if old >= 128 {
+				quantized = 255
+			}
+			quantErr := old - quantized
+
+			if x+1 < w {
+				gray[y*w+x+1] = clamp255(gray[y*w+x+1] + quantErr*7/16)
+			}
+			if y+1 < h {
+				if x-1 >= 0 {
+					gray[(y+1)*w+x-1] = clamp255(gray[(y+1)*w+x-1] + quantErr*3/16)
+				}
+				gray[(y+1)*w+x] = clamp255(gray[(y+1)*w+x] + quantErr*5/16)
+				if x+1 < w {
+					gray[(y+1)*w+x+1] = clamp255(gray[(y+1)*w+x+1] + quantErr*1/16)
+				}
+			}
+
+			if darkMode {
+				if quantized == 255 {
+					out.SetGray(bounds.Min.X+x, bounds.Min.Y+y, color.Gray{0})
+				} else {
+					out.SetGray(bounds.Min.X+x, bounds.Min.Y+y, color.Gray{255})
+				}
+			} else {
+				if quantized == 255 {
+					out.SetGray(bounds.Min.X+x, bounds.Min.Y+y, color.Gray{255})
+				} else {
+					out.SetGray(bounds.Min.X+x, bounds.Min.Y+y, color.Gray{0})
+				}
+			}
+		}
+	}
+	return out
+}
+
+func clamp255(v float64) float64 {
+	if v < 0 {
+		return 0
+	}
+	if v > 255 {
+		return 255
+	}
+	return v
+}
+
+// bayer8x8 is the standard 8x8 ordered-dither threshold matrix. E-ink holds
+// this structured pattern more cleanly than Floyd-Steinberg's noise when an
+// image is static on screen for long periods.
+var bayer8x8 = [8][8]int{
+	{0, 32, 8, 40, 2, 34, 10, 42},
+	{48, 16, 56, 24, 50, 18, 58, 26},
+	{12, 44, 4, 36, 14, 46, 6, 38},
+	{60, 28, 52, 20, 62, 30, 54, 22},
+	{3, 35, 11, 43, 1, 33, 9, 41},
+	{51, 19, 59, 27, 49, 17, 57, 25},
+	{15, 47, 7, 39, 13, 45, 5, 37},
+	{63, 31, 55, 23, 61, 29, 53, 21},
+}
+
+// orderedDither thresholds each pixel against a position-dependent value
+// from bayer8x8 instead of a fixed 128.
+func orderedDither(img image.Image, darkMode bool) *image.Gray {
+	bounds := img.Bounds()
+	out := image.NewGray(bounds)
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			r, g, b, _ := img.At(x, y).RGBA()
+			gray := uint8((r*299 + g*587 + b*114) / 1000 >> 8)
+			threshold := uint8((bayer8x8[(y-bounds.Min.Y)%8][(x-bounds.Min.X)%8] + 1) * 255 / 65)
+			if darkMode {
+				if gray < threshold {
+					out.SetGray(x, y, color.Gray{255}) // White
+				} else {
+					out.SetGray(x, y, color.Gray{0})   // Black
+				}
+			} else {
+				if gray < threshold {
+					out.SetGray(x, y, color.Gray{0})   // Black
+				} else {
+					out.SetGray(x, y, color.Gray{255}) // White
+				}
+			}
+		}
+	}
+	return out
+}
+
 func parseCommandLineArgs() AppOptions {
 	darkMode := flag.Bool("d", false, "Enable dark mode (invert monochrome images)")
 	showVersion := flag.Bool("v", false, "Show version information")
 	verbose := flag.Bool("verbose", true, "Enable verbose output")
 	quiet := flag.Bool("q", false, "Quiet mode (disable verbose output)")
+	partialRefresh := flag.Bool("partial", false, "Use partial refresh instead of a full flash on every update")
+	fullRefreshEvery := flag.Int("full-refresh-every", 10, "Force a full refresh after this many partial refreshes, to clear ghosting")
+	dither := flag.String("dither", "", `Dithering mode for grayscale conversion: "fs" (Floyd-Steinberg) or "ordered" (8x8 Bayer)`)
+	backend := flag.String("backend", "spi", `Display backend: "spi" (Waveshare panel) or "sim" (framebuffer/PNG dump, for development)`)
+	listen := flag.String("listen", "", `Address to run a local HTTP control server on (e.g. ":8080"), disabled by default`)
 	flag.Parse()
 
 	if *showVersion {
@@ -529,8 +533,13 @@ func parseCommandLineArgs() AppOptions {
 	}
 
 	return AppOptions{
-		DarkMode: *darkMode,
-		Verbose:  *verbose && !*quiet,
+		DarkMode:         *darkMode,
+		Verbose:          *verbose && !*quiet,
+		PartialRefresh:   *partialRefresh,
+		FullRefreshEvery: *fullRefreshEvery,
+		Dither:           *dither,
+		Backend:          *backend,
+		Listen:           *listen,
 	}
 }
 